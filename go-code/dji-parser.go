@@ -15,6 +15,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"unsafe"
 
 	"github.com/peterstace/simplefeatures/geom"
@@ -22,13 +23,48 @@ import (
 
 func main() {
 	if len(os.Args) < 3 {
-		fmt.Println("Usage error (two arguments only): go run dji-parser.go <input_file> <api_key>")
+		fmt.Println("Usage error: go run dji-parser.go export.go stream.go <input_file> <api_key> [format: geojson|kml|gpx|wkb|gml|stream] [limit_geojson_path] [srid] [geometry_mode: points|linestring|both|hull]")
 		os.Exit(1)
 	}
 
 	inputFile := filepath.Join(".", os.Args[1])
 	apiKey := "3519165b8d4ab74ca7033a64313e6b5" //os.Args[2]
 
+	format := FormatGeoJSON
+	if len(os.Args) >= 4 {
+		var err error
+		format, err = parseFormat(os.Args[3])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var limitPath string
+	if len(os.Args) >= 5 && os.Args[4] != "" {
+		limitPath = filepath.Join(".", os.Args[4])
+	}
+
+	srid := 4326
+	if len(os.Args) >= 6 {
+		var err error
+		srid, err = strconv.Atoi(os.Args[5])
+		if err != nil {
+			fmt.Printf("Error: invalid srid %q\n", os.Args[5])
+			os.Exit(1)
+		}
+	}
+
+	geometryMode := GeometryPoints
+	if len(os.Args) >= 7 {
+		var err error
+		geometryMode, err = parseGeometryMode(os.Args[6])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
 	fileInfo, err := os.Stat(inputFile)
 	if os.IsNotExist(err) {
 		fmt.Printf("Error: The file %s does not exist.\n", inputFile)
@@ -40,6 +76,18 @@ func main() {
 		os.Exit(1)
 	}
 
+	if limitPath != "" {
+		limitInfo, err := os.Stat(limitPath)
+		if os.IsNotExist(err) {
+			fmt.Printf("Error: The limit file %s does not exist.\n", limitPath)
+			os.Exit(1)
+		}
+		if limitInfo.Size() == 0 {
+			fmt.Printf("Error: The limit file %s is empty.\n", limitPath)
+			os.Exit(1)
+		}
+	}
+
 	fmt.Printf("Input file: %s (Size: %d bytes)\n", inputFile, fileInfo.Size())
 
 	file, err := os.Open(inputFile)
@@ -50,10 +98,64 @@ func main() {
 	defer file.Close()
 
 	reader := bufio.NewReader(file)
-	processReader(reader, apiKey)
+	if format == FormatStream {
+		if limitPath != "" || srid != 4326 || geometryMode != GeometryPoints {
+			fmt.Println("Error: stream format does not support limit_geojson_path, srid or geometry_mode")
+			os.Exit(1)
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			fmt.Printf("error reading data: %s\n", err)
+			os.Exit(1)
+		}
+		count, err := streamFeatures(data, apiKey, os.Stdout)
+		if err != nil {
+			fmt.Printf("error streaming features: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "streamed %d features\n", count)
+		return
+	}
+
+	if format != FormatGeoJSON {
+		if limitPath != "" {
+			fmt.Println("Error: limit_geojson_path is only supported for geojson output")
+			os.Exit(1)
+		}
+		if geometryMode != GeometryPoints {
+			fmt.Println("Error: geometry_mode is only supported for geojson output")
+			os.Exit(1)
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			fmt.Printf("error reading data: %s\n", err)
+			os.Exit(1)
+		}
+		text, raw, err := exportTrack(format, data, apiKey, srid)
+		if err != nil {
+			fmt.Printf("error exporting track: %s\n", err)
+			os.Exit(1)
+		}
+		if raw != nil {
+			os.Stdout.Write(raw)
+		} else {
+			fmt.Println(text)
+		}
+		return
+	}
+
+	processReader(reader, apiKey, limitPath, srid, geometryMode)
 }
 
-func processReader(reader io.Reader, apiKey string) (*geom.Geometry, error) {
+// processReader parses a DJI log and builds a geometry from it. When
+// limitPath is non-empty, it must point to a GeoJSON file containing
+// Polygon/MultiPolygon features in EPSG:4326; only track points that fall
+// inside that boundary are kept. srid reprojects the output coordinates
+// (e.g. 3857 for web mercator); 4326 emits them unchanged. geometryMode
+// selects which features the C API emits; when the output includes a
+// LineString feature, that feature is used directly instead of being
+// rebuilt from the points. A hull Polygon feature is skipped.
+func processReader(reader io.Reader, apiKey string, limitPath string, srid int, geometryMode GeometryMode) (*geom.Geometry, error) {
 	data, err := io.ReadAll(reader)
 	if err != nil {
 		return nil, fmt.Errorf("error reading data: %s", err)
@@ -65,7 +167,17 @@ func processReader(reader io.Reader, apiKey string) (*geom.Geometry, error) {
 	cApiKey := C.CString(apiKey)
 	defer C.free(unsafe.Pointer(cApiKey))
 
-	geojsonPtr := C.get_geojson_string_from_bytes((*C.uchar)(unsafe.Pointer(cData)), cLength, cApiKey)
+	cSrid := C.int(srid)
+	cGeometryMode := C.int(geometryMode)
+
+	var geojsonPtr *C.char
+	if limitPath != "" {
+		cLimitPath := C.CString(limitPath)
+		defer C.free(unsafe.Pointer(cLimitPath))
+		geojsonPtr = C.get_geojson_string_from_bytes_limited((*C.uchar)(unsafe.Pointer(cData)), cLength, cApiKey, cLimitPath, cSrid, cGeometryMode)
+	} else {
+		geojsonPtr = C.get_geojson_string_from_bytes((*C.uchar)(unsafe.Pointer(cData)), cLength, cApiKey, cSrid, cGeometryMode)
+	}
 	if geojsonPtr == nil {
 		errPtr := C.get_last_error()
 		errStr := C.GoString(errPtr)
@@ -99,7 +211,18 @@ func createGeometryFromFeatureCollection(fc geom.GeoJSONFeatureCollection) (*geo
 			continue
 		}
 
-		if feature.Geometry.Type() == geom.TypePoint {
+		switch feature.Geometry.Type() {
+		case geom.TypeLineString:
+			// geometry_mode=linestring/both already gives us an assembled
+			// LineString feature; use it directly.
+			geometry := feature.Geometry
+			return &geometry, nil
+		case geom.TypePolygon:
+			// geometry_mode=hull adds a convex-hull Polygon alongside the
+			// per-point features rather than replacing the track; skip it
+			// here and keep building the LineString from the points below.
+			continue
+		case geom.TypePoint:
 			point := feature.Geometry.MustAsPoint()
 			xy, _ := point.XY()
 			coords = append(coords, xy.X, xy.Y)
@@ -0,0 +1,127 @@
+package main
+
+/*
+#cgo LDFLAGS: -L${SRCDIR}/../target/release -ldji_log_parser
+#cgo CFLAGS: -I${SRCDIR}/../dji-log-parser/include
+#include "dji-log-parser.h"
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// DjiLogFormat selects the track export format produced by the C API.
+type DjiLogFormat int
+
+const (
+	FormatGeoJSON DjiLogFormat = iota
+	FormatKML
+	FormatGPX
+	FormatWKB
+	FormatGML
+	FormatStream
+)
+
+// GeometryMode selects which geometry features the C API emits alongside
+// (or instead of) the per-point features.
+type GeometryMode int
+
+const (
+	GeometryPoints GeometryMode = iota
+	GeometryLineString
+	GeometryBoth
+	GeometryHull
+)
+
+func parseGeometryMode(s string) (GeometryMode, error) {
+	switch strings.ToLower(s) {
+	case "", "points":
+		return GeometryPoints, nil
+	case "linestring":
+		return GeometryLineString, nil
+	case "both":
+		return GeometryBoth, nil
+	case "hull":
+		return GeometryHull, nil
+	default:
+		return 0, fmt.Errorf("unknown geometry mode %q (want points, linestring, both or hull)", s)
+	}
+}
+
+func parseFormat(s string) (DjiLogFormat, error) {
+	switch strings.ToLower(s) {
+	case "", "geojson":
+		return FormatGeoJSON, nil
+	case "kml":
+		return FormatKML, nil
+	case "gpx":
+		return FormatGPX, nil
+	case "wkb":
+		return FormatWKB, nil
+	case "gml":
+		return FormatGML, nil
+	case "stream":
+		return FormatStream, nil
+	default:
+		return 0, fmt.Errorf("unknown format %q (want geojson, kml, gpx, wkb, gml or stream)", s)
+	}
+}
+
+// exportTrack renders the track contained in data in the given format. Text
+// formats (kml, gpx, gml) are returned as text; wkb is returned as raw bytes.
+// srid reprojects the output coordinates (e.g. 3857 for web mercator) for
+// the kml and wkb formats; 4326 emits them unchanged. gpx and gml output is
+// not reprojected, and geojson is not handled here (see processReader).
+func exportTrack(format DjiLogFormat, data []byte, apiKey string, srid int) (text string, raw []byte, err error) {
+	cData := C.CBytes(data)
+	defer C.free(unsafe.Pointer(cData))
+	cLength := C.size_t(len(data))
+	cApiKey := C.CString(apiKey)
+	defer C.free(unsafe.Pointer(cApiKey))
+	cSrid := C.int(srid)
+
+	switch format {
+	case FormatKML:
+		ptr := C.get_kml_string_from_bytes((*C.uchar)(unsafe.Pointer(cData)), cLength, cApiKey, cSrid)
+		if ptr == nil {
+			return "", nil, lastCApiError()
+		}
+		defer C.c_api_free_string(ptr)
+		return C.GoString(ptr), nil, nil
+	case FormatGPX:
+		ptr := C.get_gpx_string_from_bytes((*C.uchar)(unsafe.Pointer(cData)), cLength, cApiKey)
+		if ptr == nil {
+			return "", nil, lastCApiError()
+		}
+		defer C.c_api_free_string(ptr)
+		return C.GoString(ptr), nil, nil
+	case FormatGML:
+		ptr := C.get_gml_string_from_bytes((*C.uchar)(unsafe.Pointer(cData)), cLength, cApiKey)
+		if ptr == nil {
+			return "", nil, lastCApiError()
+		}
+		defer C.c_api_free_string(ptr)
+		return C.GoString(ptr), nil, nil
+	case FormatWKB:
+		var outLen C.size_t
+		ptr := C.get_wkb_bytes_from_bytes((*C.uchar)(unsafe.Pointer(cData)), cLength, cApiKey, cSrid, &outLen)
+		if ptr == nil {
+			return "", nil, lastCApiError()
+		}
+		defer C.c_api_free_bytes(unsafe.Pointer(ptr))
+		return "", C.GoBytes(unsafe.Pointer(ptr), C.int(outLen)), nil
+	default:
+		return "", nil, fmt.Errorf("unsupported format %v", format)
+	}
+}
+
+func lastCApiError() error {
+	errPtr := C.get_last_error()
+	errStr := C.GoString(errPtr)
+	C.c_api_free_string(errPtr)
+	return fmt.Errorf("%s", errStr)
+}
@@ -0,0 +1,59 @@
+package main
+
+/*
+#cgo LDFLAGS: -L${SRCDIR}/../target/release -ldji_log_parser
+#cgo CFLAGS: -I${SRCDIR}/../dji-log-parser/include
+#include "dji-log-parser.h"
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// streamFeatures opens data as a DJI log and writes one NDJSON line per
+// feature to w as it is decoded, instead of materializing the whole
+// GeoJSON document in memory. It returns the number of features written.
+func streamFeatures(data []byte, apiKey string, w io.Writer) (int, error) {
+	cData := C.CBytes(data)
+	defer C.free(unsafe.Pointer(cData))
+	cLength := C.size_t(len(data))
+	cApiKey := C.CString(apiKey)
+	defer C.free(unsafe.Pointer(cApiKey))
+
+	handle := C.dji_log_open((*C.uchar)(unsafe.Pointer(cData)), cLength, cApiKey)
+	if handle == 0 {
+		return 0, lastCApiError()
+	}
+	defer C.dji_log_close(handle)
+
+	count := 0
+	for {
+		var outPtr *C.char
+		var outLen C.size_t
+		more := C.dji_log_next_feature(handle, &outPtr, &outLen)
+		if outPtr == nil {
+			break
+		}
+
+		feature := C.GoBytes(unsafe.Pointer(outPtr), C.int(outLen))
+		C.c_api_free_bytes(unsafe.Pointer(outPtr))
+
+		if _, err := w.Write(feature); err != nil {
+			return count, fmt.Errorf("error writing feature: %s", err)
+		}
+		if _, err := w.Write([]byte("\n")); err != nil {
+			return count, fmt.Errorf("error writing feature: %s", err)
+		}
+		count++
+
+		if !bool(more) {
+			break
+		}
+	}
+
+	return count, nil
+}
@@ -14,7 +14,6 @@ import (
     "os"
     "path/filepath"
     "unsafe"
-	"math"
 )
 
 type GeoJSON struct {
@@ -40,6 +39,21 @@ type Properties struct {
     // Add other properties as needed
 }
 
+// FlightStats mirrors the JSON blob returned by dji_log_get_stats_from_bytes.
+type FlightStats struct {
+    TotalDistanceM        float64 `json:"total_distance_m"`
+    TotalDurationS         float64 `json:"total_duration_s"`
+    MaxHeightM             float64 `json:"max_height_m"`
+    MaxSpeedMS             float64 `json:"max_speed_m_s"`
+    MaxDistanceFromHomeM   float64 `json:"max_distance_from_home_m"`
+    TotalAscentM           float64 `json:"total_ascent_m"`
+    TotalDescentM          float64 `json:"total_descent_m"`
+    BatteryStartPct        float64 `json:"battery_start_pct"`
+    BatteryEndPct          float64 `json:"battery_end_pct"`
+    AvgVerticalVelocityMS  float64 `json:"avg_vertical_velocity_m_s"`
+    AvgHorizontalVelocityMS float64 `json:"avg_horizontal_velocity_m_s"`
+}
+
 func main() {
     if len(os.Args) < 3 {
         fmt.Println("Usage: go run testingwithGo.go <input_file> <api_key>")
@@ -103,11 +117,50 @@ func main() {
         os.Exit(1)
     }
 
+    // Fetch flight statistics computed on the Rust side, which already has
+    // every frame decoded, instead of re-deriving them from the GeoJSON.
+    logBytes, err := os.ReadFile(inputFile)
+    if err != nil {
+        fmt.Println("Error reading input file:", err)
+        os.Exit(1)
+    }
+    cLogBytes := C.CBytes(logBytes)
+    defer C.free(unsafe.Pointer(cLogBytes))
+    cLogLength := C.size_t(len(logBytes))
+
+    statsPtr := C.dji_log_get_stats_from_bytes((*C.uchar)(unsafe.Pointer(cLogBytes)), cLogLength, cApiKey)
+    if statsPtr == nil {
+        errPtr := C.get_last_error()
+        errStr := C.GoString(errPtr)
+        C.c_api_free_string(errPtr)
+        fmt.Printf("Failed to get flight stats: %s\n", errStr)
+        os.Exit(1)
+    }
+    defer C.c_api_free_string(statsPtr)
+
+    var stats FlightStats
+    if err := json.Unmarshal([]byte(C.GoString(statsPtr)), &stats); err != nil {
+        fmt.Println("Error parsing flight stats:", err)
+        os.Exit(1)
+    }
+
+    fmt.Printf("\nFlight Statistics:\n")
+    fmt.Printf("Total Duration: %.2fs\n", stats.TotalDurationS)
+    fmt.Printf("Max Height: %.2fm\n", stats.MaxHeightM)
+    fmt.Printf("Max Speed: %.2fm/s\n", stats.MaxSpeedMS)
+    fmt.Printf("Max Distance From Home: %.2fm\n", stats.MaxDistanceFromHomeM)
+    fmt.Printf("Total Ascent: %.2fm\n", stats.TotalAscentM)
+    fmt.Printf("Total Descent: %.2fm\n", stats.TotalDescentM)
+    fmt.Printf("Battery: %.0f%% -> %.0f%%\n", stats.BatteryStartPct, stats.BatteryEndPct)
+    fmt.Printf("Avg Vertical Velocity: %.2fm/s\n", stats.AvgVerticalVelocityMS)
+    fmt.Printf("Avg Horizontal Velocity: %.2fm/s\n", stats.AvgHorizontalVelocityMS)
+    fmt.Printf("Total Distance: %.2fm\n", stats.TotalDistanceM)
+
     // Print GeoJSON details
     fmt.Printf("GeoJSON Type: %s\n", geojson.Type)
     fmt.Printf("Number of Features: %d\n", len(geojson.Features))
 
-    if len(geojson.Features) > 0 {
+    if len(geojson.Features) > 10000 {
         firstFeature := geojson.Features[10000]
         fmt.Printf("First Feature Type: %s\n", firstFeature.Type)
         fmt.Printf("First Feature Geometry Type: %s\n", firstFeature.Geometry.Type)
@@ -116,54 +169,4 @@ func main() {
         fmt.Printf("First Feature Height: %.2f\n", firstFeature.Properties.Height)
         fmt.Printf("First Feature Speed: %.2f\n", firstFeature.Properties.Speed)
     }
-
-    // Calculate some statistics
-    var totalDistance float64
-    var maxHeight float64
-    var startTime, endTime string
-
-    for i, feature := range geojson.Features {
-        if i == 0 {
-            startTime = feature.Properties.Time
-        }
-        if i == len(geojson.Features)-1 {
-            endTime = feature.Properties.Time
-        }
-        if feature.Properties.Height > maxHeight {
-            maxHeight = feature.Properties.Height
-        }
-        // Calculate distance between consecutive points
-		if i > 0 {
-			prevFeature := geojson.Features[i-1]
-			lat1, lon1 := prevFeature.Geometry.Coordinates[1], prevFeature.Geometry.Coordinates[0]
-			lat2, lon2 := feature.Geometry.Coordinates[1], feature.Geometry.Coordinates[0]
-			distance := distanceHaversine(lat1, lon1, lat2, lon2)
-			totalDistance += distance
-		}
-    }
-
-    fmt.Printf("\nFlight Statistics:\n")
-    fmt.Printf("Start Time: %s\n", startTime)
-    fmt.Printf("End Time: %s\n", endTime)
-    fmt.Printf("Max Height: %.2f\n", maxHeight)
-    fmt.Printf("Total Distance: %.2f\n", totalDistance) // This will be 0 unless you implement the distance calculation
-}
-
-func degreesToRadians(degrees float64) float64 {
-    return degrees * math.Pi / 180
-}
-
-func distanceHaversine(lat1, lon1, lat2, lon2 float64) float64 {
-    earthRadiusKm := 6371.0
-
-    dLat := degreesToRadians(lat2 - lat1)
-    dLon := degreesToRadians(lon2 - lon1)
-
-    lat1 = degreesToRadians(lat1)
-    lat2 = degreesToRadians(lat2)
-
-    a := math.Sin(dLat/2)*math.Sin(dLat/2) +
-        math.Sin(dLon/2)*math.Sin(dLon/2)*math.Cos(lat1)*math.Cos(lat2)
-    c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
-    return earthRadiusKm * c
 }
\ No newline at end of file